@@ -0,0 +1,263 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// AlertPlatform 标识告警要推送到的 IM 机器人类型。
+type AlertPlatform string
+
+const (
+	AlertPlatformFeishu   AlertPlatform = "feishu"
+	AlertPlatformWeCom    AlertPlatform = "wecom"
+	AlertPlatformSlack    AlertPlatform = "slack"
+	AlertPlatformTelegram AlertPlatform = "telegram"
+)
+
+const (
+	defaultAlertMinLevel = WarnLevel
+	defaultAlertFlushSec = 5
+	defaultAlertMaxBatch = 20
+	alertChannelSize     = 500
+)
+
+// AlertCfg 配置高危日志异步推送到 IM 机器人的行为。
+type AlertCfg struct {
+	Type     AlertPlatform `json:"type" yaml:"type"`           // feishu/wecom/slack/telegram
+	Webhook  string        `json:"webhook" yaml:"webhook"`     // 机器人 webhook 地址
+	Token    string        `json:"token" yaml:"token"`         // bot token（telegram 等需要）
+	ChatID   string        `json:"chat_id" yaml:"chat_id"`     // telegram chat id
+	MinLevel LogLevel      `json:"min_level" yaml:"min_level"` // 达到该级别才告警，默认 warn
+	FlushSec int           `json:"flush_sec" yaml:"flush_sec"` // 批量发送时间阈值（秒），默认 5
+	MaxBatch int           `json:"max_batch" yaml:"max_batch"` // 批量发送条数阈值，默认 20
+	Template string        `json:"template" yaml:"template"`   // 自定义消息模板（留给自定义 formatter 使用）
+}
+
+// AlertEntry 是一条待推送给 IM 机器人的日志记录。
+type AlertEntry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Fields  map[string]any
+}
+
+// AlertFormatter 把一批 AlertEntry 编码为某个平台要求的请求体。
+type AlertFormatter func(entries []AlertEntry) (body []byte, contentType string, err error)
+
+var (
+	alertFormattersMu sync.RWMutex
+	alertFormatters   = map[AlertPlatform]AlertFormatter{
+		AlertPlatformFeishu:   formatFeishuAlert,
+		AlertPlatformWeCom:    formatWeComAlert,
+		AlertPlatformSlack:    formatSlackAlert,
+		AlertPlatformTelegram: formatTelegramAlert,
+	}
+)
+
+// RegisterAlertFormatter 注册或覆盖某个平台的消息格式化函数，便于自定义卡片样式。
+func RegisterAlertFormatter(platform AlertPlatform, f AlertFormatter) {
+	alertFormattersMu.Lock()
+	defer alertFormattersMu.Unlock()
+	alertFormatters[platform] = f
+}
+
+func getAlertFormatter(platform AlertPlatform) AlertFormatter {
+	alertFormattersMu.RLock()
+	defer alertFormattersMu.RUnlock()
+	return alertFormatters[platform]
+}
+
+func alertSummary(entries []AlertEntry) string {
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		line := fmt.Sprintf("[%s] %s", e.Level, e.Message)
+		for k, v := range e.Fields {
+			line += fmt.Sprintf(" %s=%v", k, v)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatFeishuAlert(entries []AlertEntry) ([]byte, string, error) {
+	body, err := json.Marshal(map[string]any{
+		"msg_type": "text",
+		"content":  map[string]any{"text": alertSummary(entries)},
+	})
+	return body, "application/json", err
+}
+
+func formatWeComAlert(entries []AlertEntry) ([]byte, string, error) {
+	body, err := json.Marshal(map[string]any{
+		"msgtype": "text",
+		"text":    map[string]any{"content": alertSummary(entries)},
+	})
+	return body, "application/json", err
+}
+
+func formatSlackAlert(entries []AlertEntry) ([]byte, string, error) {
+	body, err := json.Marshal(map[string]any{"text": alertSummary(entries)})
+	return body, "application/json", err
+}
+
+func formatTelegramAlert(entries []AlertEntry) ([]byte, string, error) {
+	// chat_id 由 alertSink 在发送时补充，formatter 只负责消息正文。
+	body, err := json.Marshal(map[string]any{"text": alertSummary(entries)})
+	return body, "application/json", err
+}
+
+// alertCore 是一个额外的 zapcore.Core，只转发达到 MinLevel 的记录给 alertSink。
+type alertCore struct {
+	threshold zapcore.Level
+	sink      *alertSink
+	fields    []zapcore.Field
+}
+
+func newAlertCore(cfg *AlertCfg) *alertCore {
+	minLevel := cfg.MinLevel
+	if minLevel == "" {
+		minLevel = defaultAlertMinLevel
+	}
+	return &alertCore{
+		threshold: minLevel.LogLevel(),
+		sink:      newAlertSink(cfg),
+	}
+}
+
+func (c *alertCore) Enabled(level zapcore.Level) bool {
+	return level >= c.threshold
+}
+
+func (c *alertCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &alertCore{threshold: c.threshold, sink: c.sink, fields: merged}
+}
+
+func (c *alertCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *alertCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range merged {
+		f.AddTo(enc)
+	}
+
+	c.sink.enqueue(AlertEntry{
+		Time:    ent.Time,
+		Level:   ent.Level.CapitalString(),
+		Message: ent.Message,
+		Fields:  enc.Fields,
+	})
+	return nil
+}
+
+func (c *alertCore) Sync() error { return nil }
+
+// alertSink 缓冲告警记录并在后台按批量/时间阈值推送给 IM 机器人。
+type alertSink struct {
+	cfg       *AlertCfg
+	formatter AlertFormatter
+	client    *http.Client
+
+	b *batcher[AlertEntry]
+}
+
+func newAlertSink(cfg *AlertCfg) *alertSink {
+	maxBatch := cfg.MaxBatch
+	if maxBatch <= 0 {
+		maxBatch = defaultAlertMaxBatch
+	}
+	flushSec := cfg.FlushSec
+	if flushSec <= 0 {
+		flushSec = defaultAlertFlushSec
+	}
+
+	s := &alertSink{
+		cfg:       cfg,
+		formatter: getAlertFormatter(cfg.Type),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+	s.b = newBatcher(alertChannelSize, maxBatch, time.Duration(flushSec)*time.Second, s.deliver)
+	return s
+}
+
+func (s *alertSink) enqueue(e AlertEntry) {
+	s.b.enqueue(e)
+}
+
+// DroppedCount 返回因 channel 拥塞而被丢弃的告警条数。
+func (s *alertSink) DroppedCount() int64 {
+	return s.b.droppedCount()
+}
+
+func (s *alertSink) Close() {
+	s.b.close()
+}
+
+// deliver 格式化并发送一批告警；失败时只在本地打印，绝不经由日志系统递归写入。
+func (s *alertSink) deliver(entries []AlertEntry) {
+	if s.formatter == nil {
+		fmt.Fprintf(os.Stderr, "logger: no alert formatter registered for platform %q\n", s.cfg.Type)
+		return
+	}
+	body, contentType, err := s.formatter(entries)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger: format alert batch failed: %v\n", err)
+		return
+	}
+	if err := s.send(body, contentType); err != nil {
+		fmt.Fprintf(os.Stderr, "logger: deliver alert failed: %v\n", err)
+	}
+}
+
+func (s *alertSink) send(body []byte, contentType string) error {
+	url := s.cfg.Webhook
+	if s.cfg.Type == AlertPlatformTelegram {
+		var payload map[string]any
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return err
+		}
+		payload["chat_id"] = s.cfg.ChatID
+		var err error
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		url = fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", s.cfg.Token)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
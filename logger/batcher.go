@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// batcher 是 Loki/告警等异步 sink 共用的批量推送引擎：把条目写入一个有界 channel，
+// 后台 goroutine 按“条数达到阈值”或“定时器触发”把缓冲交给 deliver 推送；
+// channel 写满时按 drop-oldest 策略丢弃最旧的一条，保证写日志的调用方不被阻塞，
+// 丢弃数通过 droppedCount 暴露。关闭时排空 channel 中剩余条目后再退出。
+type batcher[T any] struct {
+	batchSize int
+	flushEach time.Duration
+	deliver   func([]T)
+
+	ch      chan T
+	dropped int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newBatcher[T any](channelSize, batchSize int, flushEach time.Duration, deliver func([]T)) *batcher[T] {
+	b := &batcher[T]{
+		batchSize: batchSize,
+		flushEach: flushEach,
+		deliver:   deliver,
+		ch:        make(chan T, channelSize),
+		done:      make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go b.run()
+	return b
+}
+
+// enqueue 尝试写入一条待推送的条目；channel 已满时丢弃最旧的一条腾出空间。
+func (b *batcher[T]) enqueue(e T) {
+	select {
+	case b.ch <- e:
+	default:
+		select {
+		case <-b.ch:
+			atomic.AddInt64(&b.dropped, 1)
+		default:
+		}
+		select {
+		case b.ch <- e:
+		default:
+			atomic.AddInt64(&b.dropped, 1)
+		}
+	}
+}
+
+// droppedCount 返回因 channel 拥塞而被丢弃的条目数。
+func (b *batcher[T]) droppedCount() int64 {
+	return atomic.LoadInt64(&b.dropped)
+}
+
+// close 停止后台 goroutine 并在退出前尽力推送剩余缓冲。
+func (b *batcher[T]) close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+		b.wg.Wait()
+	})
+}
+
+func (b *batcher[T]) run() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.flushEach)
+	defer ticker.Stop()
+
+	buf := make([]T, 0, b.batchSize)
+	flush := func() {
+		if len(buf) == 0 {
+			return
+		}
+		b.deliver(buf)
+		buf = buf[:0]
+	}
+
+	for {
+		select {
+		case e := <-b.ch:
+			buf = append(buf, e)
+			if len(buf) >= b.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-b.done:
+			for { // 排空 channel 中剩余的条目后再退出
+				select {
+				case e := <-b.ch:
+					buf = append(buf, e)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
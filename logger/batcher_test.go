@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestBatcherEnqueueDropsOldestWhenFull 验证 channel 写满后按 drop-oldest 策略
+// 丢弃最旧的一条，且丢弃数被正确计入 droppedCount。
+func TestBatcherEnqueueDropsOldestWhenFull(t *testing.T) {
+	b := &batcher[int]{ch: make(chan int, 1), done: make(chan struct{})}
+	b.enqueue(1)
+	b.enqueue(2) // channel 已满：丢弃 1，保留 2
+	b.enqueue(3) // channel 已满：丢弃 2，保留 3
+
+	if got := b.droppedCount(); got != 2 {
+		t.Fatalf("droppedCount = %d, want 2", got)
+	}
+	select {
+	case v := <-b.ch:
+		if v != 3 {
+			t.Fatalf("channel head = %d, want 3", v)
+		}
+	default:
+		t.Fatal("expected one buffered entry")
+	}
+}
+
+// TestBatcherFlushOnBatchSize 验证缓冲条数达到 batchSize 时立即触发一次 flush。
+func TestBatcherFlushOnBatchSize(t *testing.T) {
+	var mu sync.Mutex
+	var got [][]int
+	flushed := make(chan struct{}, 10)
+
+	b := newBatcher(10, 2, time.Hour, func(batch []int) {
+		cp := append([]int(nil), batch...)
+		mu.Lock()
+		got = append(got, cp)
+		mu.Unlock()
+		flushed <- struct{}{}
+	})
+	defer b.close()
+
+	b.enqueue(1)
+	b.enqueue(2)
+
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Fatal("expected flush triggered by batch size")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || len(got[0]) != 2 {
+		t.Fatalf("got = %v, want one batch of 2", got)
+	}
+}
+
+// TestBatcherFlushOnTimer 验证未达到 batchSize 时仍会被定时器周期性 flush。
+func TestBatcherFlushOnTimer(t *testing.T) {
+	flushed := make(chan []int, 1)
+	b := newBatcher(10, 100, 20*time.Millisecond, func(batch []int) {
+		flushed <- append([]int(nil), batch...)
+	})
+	defer b.close()
+
+	b.enqueue(42)
+
+	select {
+	case batch := <-flushed:
+		if len(batch) != 1 || batch[0] != 42 {
+			t.Fatalf("batch = %v, want [42]", batch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected flush triggered by timer")
+	}
+}
+
+// TestBatcherCloseFlushesRemaining 验证 close 会排空 channel 并推送剩余缓冲后再退出。
+func TestBatcherCloseFlushesRemaining(t *testing.T) {
+	flushed := make(chan []int, 1)
+	b := newBatcher(10, 100, time.Hour, func(batch []int) {
+		flushed <- append([]int(nil), batch...)
+	})
+
+	b.enqueue(7)
+	b.close()
+
+	select {
+	case batch := <-flushed:
+		if len(batch) != 1 || batch[0] != 7 {
+			t.Fatalf("batch = %v, want [7]", batch)
+		}
+	default:
+		t.Fatal("expected close to flush buffered entry")
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	RotateBySize   = "size"   // 默认：只按 lumberjack 的体积策略轮转
+	RotateByDaily  = "daily"  // 按天切换文件
+	RotateByHourly = "hourly" // 按小时切换文件
+)
+
+// dateRotateWriter 实现 zapcore.WriteSyncer：把 TimePattern 按当前时间格式化出文件名，
+// 一旦格式化结果发生变化（跨天/跨小时）就原子地切到新文件；同一时间段内的体积溢出仍交
+// 给 lumberjack 处理。
+type dateRotateWriter struct {
+	pattern string
+	cfg     *LoggerCfg
+
+	mu      sync.Mutex
+	current string
+	out     *lumberjack.Logger
+}
+
+func newDateRotateWriter(cfg *LoggerCfg, fallbackPath string) *dateRotateWriter {
+	pattern := cfg.TimePattern
+	if pattern == "" {
+		pattern = fallbackPath
+	}
+	return &dateRotateWriter{pattern: pattern, cfg: cfg}
+}
+
+// Write 实现 zapcore.WriteSyncer，按需切换到当前时间对应的文件后再写入；切换判断和
+// 实际写入在同一把锁下完成，避免拿到切换前的 *lumberjack.Logger 后被并发的轮转关闭。
+func (w *dateRotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotatedLocked().Write(p)
+}
+
+// Sync 实现 zapcore.WriteSyncer；lumberjack 本身没有缓冲，这里与其余 writer 保持一致，不做事。
+func (w *dateRotateWriter) Sync() error {
+	return nil
+}
+
+// rotatedLocked 返回当前时间对应的 lumberjack.Logger，必要时创建新文件并关闭旧文件；
+// 调用方必须持有 w.mu。
+func (w *dateRotateWriter) rotatedLocked() *lumberjack.Logger {
+	name := time.Now().Format(w.pattern)
+
+	if w.out != nil && name == w.current {
+		return w.out
+	}
+
+	if dir := filepath.Dir(name); dir != "." {
+		_ = os.MkdirAll(dir, 0o755)
+	}
+
+	prev := w.out
+	w.out = &lumberjack.Logger{
+		Filename:   name,
+		MaxSize:    w.cfg.Maxsize,
+		MaxBackups: w.cfg.Maxbackups,
+		MaxAge:     w.cfg.Maxage,
+		Compress:   w.cfg.Compress,
+	}
+	w.current = name
+
+	if prev != nil {
+		_ = prev.Close() // 落盘并释放旧文件句柄
+	}
+	return w.out
+}
+
+// Close 关闭当前持有的文件。
+func (w *dateRotateWriter) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.out != nil {
+		_ = w.out.Close()
+	}
+}
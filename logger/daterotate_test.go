@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestDateRotateWriterReturnsStableInstanceUntilPatternChanges 验证同一时间段内
+// 多次调用复用同一个 *lumberjack.Logger，不会无谓地重新创建文件句柄。
+func TestDateRotateWriterReturnsStableInstanceUntilPatternChanges(t *testing.T) {
+	dir := t.TempDir()
+	w := newDateRotateWriter(&LoggerCfg{}, filepath.Join(dir, "app.log"))
+	defer w.Close()
+
+	first := w.rotatedLocked()
+	second := w.rotatedLocked()
+	if first != second {
+		t.Fatal("expected the same lumberjack.Logger while the formatted name is unchanged")
+	}
+}
+
+// TestDateRotateWriterSwapsFileWhenPatternChanges 验证格式化出的文件名变化后会
+// 切换到新的 *lumberjack.Logger 并关闭旧的。
+func TestDateRotateWriterSwapsFileWhenPatternChanges(t *testing.T) {
+	dir := t.TempDir()
+	w := newDateRotateWriter(&LoggerCfg{}, filepath.Join(dir, "app-2006-01-02.log"))
+	defer w.Close()
+
+	first := w.rotatedLocked()
+	firstName := w.current
+
+	w.current = "forced-previous-period" // 模拟已跨过一个时间段，而不必真的等到日期边界
+	second := w.rotatedLocked()
+
+	if first == second {
+		t.Fatal("expected a new writer once the formatted name changes")
+	}
+	if w.current != firstName {
+		t.Fatalf("current = %q, want %q", w.current, firstName)
+	}
+}
+
+// TestDateRotateWriterConcurrentWrite 验证并发 Write 不会在轮转边界上发生数据竞争
+// 或写入已被关闭的旧文件句柄。
+func TestDateRotateWriterConcurrentWrite(t *testing.T) {
+	dir := t.TempDir()
+	w := newDateRotateWriter(&LoggerCfg{}, filepath.Join(dir, "app.log"))
+	defer w.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := w.Write([]byte("line\n")); err != nil {
+				t.Errorf("Write failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const defaultEncoderFormat = "console"
+
+// EncoderOptions 控制单个 core 编码器的外观，零值即为原有的默认行为。
+type EncoderOptions struct {
+	TimeLayout string `json:"time_layout" yaml:"time_layout"` // time.Format 布局，默认 ISO8601
+	LevelStyle string `json:"level_style" yaml:"level_style"` // "capital"(默认)/"capitalColor"/"lower"/"lowerColor"
+	Color      bool   `json:"color" yaml:"color"`             // 未显式设置 level_style 时，是否给级别加颜色
+	CallerKey  string `json:"caller_key" yaml:"caller_key"`   // 默认 "caller"
+	TimeKey    string `json:"time_key" yaml:"time_key"`       // 默认 "time"
+}
+
+// EncoderFactory 根据 EncoderOptions 构建一个 zapcore.Encoder。
+type EncoderFactory func(opts EncoderOptions) zapcore.Encoder
+
+var (
+	encoderFactoriesMu sync.RWMutex
+	encoderFactories   = map[string]EncoderFactory{
+		"console": newConsoleEncoder,
+		"json":    newJSONEncoder,
+	}
+)
+
+// RegisterEncoder 注册或覆盖一种编码器格式，便于接入 logfmt/GELF/ECS 等自定义格式而无需 fork。
+func RegisterEncoder(name string, factory EncoderFactory) {
+	encoderFactoriesMu.Lock()
+	defer encoderFactoriesMu.Unlock()
+	encoderFactories[strings.ToLower(strings.TrimSpace(name))] = factory
+}
+
+func getEncoderFactory(name string) (EncoderFactory, bool) {
+	encoderFactoriesMu.RLock()
+	defer encoderFactoriesMu.RUnlock()
+	f, ok := encoderFactories[name]
+	return f, ok
+}
+
+func baseEncoderConfig(opts EncoderOptions) zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+
+	if opts.TimeLayout == "" {
+		cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	} else {
+		cfg.EncodeTime = zapcore.TimeEncoderOfLayout(opts.TimeLayout)
+	}
+
+	switch strings.ToLower(opts.LevelStyle) {
+	case "lower":
+		cfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+	case "lowercolor":
+		cfg.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+	case "capitalcolor":
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	case "capital", "":
+		if opts.Color {
+			cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		} else {
+			cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		}
+	default:
+		cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+
+	cfg.CallerKey = "caller"
+	if opts.CallerKey != "" {
+		cfg.CallerKey = opts.CallerKey
+	}
+	cfg.TimeKey = "time"
+	if opts.TimeKey != "" {
+		cfg.TimeKey = opts.TimeKey
+	}
+
+	return cfg
+}
+
+func newConsoleEncoder(opts EncoderOptions) zapcore.Encoder {
+	return zapcore.NewConsoleEncoder(baseEncoderConfig(opts))
+}
+
+func newJSONEncoder(opts EncoderOptions) zapcore.Encoder {
+	return zapcore.NewJSONEncoder(baseEncoderConfig(opts))
+}
+
+// defaultEncoder 是 GetLogger 等无配置场景下使用的兜底编码器，行为与原来的 getEncoder 一致。
+func defaultEncoder() zapcore.Encoder {
+	return newConsoleEncoder(EncoderOptions{})
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLokiBatchSize     = 100
+	defaultLokiFlushInterval = 3 * time.Second
+	lokiChannelSize          = 1000
+	lokiMaxRetries           = 3
+	lokiRetryBaseDelay       = 200 * time.Millisecond
+)
+
+// lokiEntry 是缓冲区中待推送的一条日志。
+type lokiEntry struct {
+	ts   time.Time
+	line string
+}
+
+// lokiWriter 实现 zapcore.WriteSyncer，将编码后的日志行缓冲、分批后推送到 Loki。
+// 所有推送都在 batcher 的后台 goroutine 中完成，不会阻塞写日志的业务 goroutine。
+type lokiWriter struct {
+	url      string
+	tenantID string
+	labels   map[string]string
+
+	client *http.Client
+
+	b *batcher[lokiEntry]
+}
+
+func newLokiWriter(cfg *LoggerCfg) *lokiWriter {
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultLokiBatchSize
+	}
+	flushEach := cfg.FlushInterval
+	if flushEach <= 0 {
+		flushEach = defaultLokiFlushInterval
+	}
+
+	w := &lokiWriter{
+		url:      cfg.LokiURL,
+		tenantID: cfg.TenantID,
+		labels:   cfg.LokiLabels,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	w.b = newBatcher(lokiChannelSize, batchSize, flushEach, w.push)
+	return w
+}
+
+// Write 实现 zapcore.WriteSyncer。
+func (w *lokiWriter) Write(p []byte) (int, error) {
+	w.b.enqueue(lokiEntry{ts: time.Now(), line: string(bytes.TrimRight(p, "\n"))})
+	return len(p), nil
+}
+
+// Sync 实现 zapcore.WriteSyncer；实际的推送由后台 goroutine 按批量/时间阈值完成。
+func (w *lokiWriter) Sync() error {
+	return nil
+}
+
+// DroppedCount 返回因 channel 拥塞而被丢弃的日志条数。
+func (w *lokiWriter) DroppedCount() int64 {
+	return w.b.droppedCount()
+}
+
+// Close 停止后台 goroutine 并在退出前尽力推送剩余缓冲。
+func (w *lokiWriter) Close() {
+	w.b.close()
+}
+
+// push 将一批日志编码为 Loki push API 所需的 JSON 并发送，瞬时错误采用有界指数退避重试。
+func (w *lokiWriter) push(entries []lokiEntry) {
+	body, err := w.encode(entries)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger: encode loki batch failed: %v\n", err)
+		return
+	}
+
+	delay := lokiRetryBaseDelay
+	for attempt := 0; attempt <= lokiMaxRetries; attempt++ {
+		if err = w.send(body); err == nil {
+			return
+		}
+		if attempt == lokiMaxRetries {
+			break
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+	fmt.Fprintf(os.Stderr, "logger: push to loki failed after retries: %v\n", err)
+}
+
+func (w *lokiWriter) encode(entries []lokiEntry) ([]byte, error) {
+	values := make([][2]string, 0, len(entries))
+	for _, e := range entries {
+		values = append(values, [2]string{strconv.FormatInt(e.ts.UnixNano(), 10), e.line})
+	}
+	payload := map[string]any{
+		"streams": []map[string]any{
+			{
+				"stream": w.labels,
+				"values": values,
+			},
+		},
+	}
+	return json.Marshal(payload)
+}
+
+func (w *lokiWriter) send(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", w.tenantID)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("loki push returned status %d", resp.StatusCode)
+	}
+	return nil
+}
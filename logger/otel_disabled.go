@@ -0,0 +1,12 @@
+//go:build !otel
+
+package logger
+
+import "context"
+
+// extractOtelSpan 是未启用 otel 构建标签时的默认实现，始终返回 ok=false，
+// 调用方会回退到原有的 TraceIDKey UUID 行为。这样不需要 OTel 的用户不会被
+// 强制引入 go.opentelemetry.io/otel 依赖。
+func extractOtelSpan(ctx context.Context) (traceID, spanID, traceFlags string, ok bool) {
+	return "", "", "", false
+}
@@ -0,0 +1,19 @@
+//go:build otel
+
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// extractOtelSpan 在启用 otel 构建标签时，从 ctx 中提取当前 OTel span 的
+// traceId/spanId/traceFlags；ctx 中没有有效 span 时返回 ok=false。
+func extractOtelSpan(ctx context.Context) (traceID, spanID, traceFlags string, ok bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), sc.TraceFlags().String(), true
+}
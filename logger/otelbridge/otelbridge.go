@@ -0,0 +1,67 @@
+// Package otelbridge 把 logger 包产生的日志记录同时挂载为当前 OTel span 的
+// span event，便于在追踪系统里直接看到关键日志。它是一个独立子包：只有显式
+// 导入 otelbridge 的用户才会拉入 go.opentelemetry.io/otel 依赖。
+package otelbridge
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// Wrap 返回一个新的 zapcore.Core：写入时先把记录转发给 base，再把它作为
+// span event 挂到 ctx 中的当前 span 上（ctx 中没有有效 span 时只转发给 base）。
+func Wrap(base zapcore.Core, ctx context.Context) zapcore.Core {
+	return &core{Core: base, ctx: ctx}
+}
+
+type core struct {
+	zapcore.Core
+	ctx context.Context
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(fields), ctx: c.ctx}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if span := trace.SpanFromContext(c.ctx); span.SpanContext().IsValid() {
+		span.AddEvent(ent.Message, trace.WithAttributes(
+			attribute.String("log.severity", ent.Level.String()),
+			attribute.Int("log.severity_number", severityNumber(ent.Level)),
+		))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// severityNumber 把 zap 的日志级别映射为 OTel 日志规范中的 severity number。
+// 参考 OTel Logs Data Model：https://opentelemetry.io/docs/specs/otel/logs/data-model/#field-severitynumber
+func severityNumber(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 5
+	case zapcore.InfoLevel:
+		return 9
+	case zapcore.WarnLevel:
+		return 13
+	case zapcore.ErrorLevel:
+		return 17
+	case zapcore.DPanicLevel:
+		return 21
+	case zapcore.PanicLevel:
+		return 21
+	case zapcore.FatalLevel:
+		return 21
+	default:
+		return 0
+	}
+}
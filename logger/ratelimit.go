@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Stats 汇总 logger 运行期间各类丢弃计数，便于监控热点日志路径是否失控。
+type Stats struct {
+	LokiDropped        int64            // Loki sink 因 channel 拥塞被丢弃的条数
+	AlertDropped       int64            // 告警 sink 因 channel 拥塞被丢弃的条数
+	RateLimited        int64            // 被 RateLimitPerSec 限流丢弃的总条数
+	RateLimitedByLevel map[string]int64 // 按级别拆分的限流丢弃数
+}
+
+// rateLimitCore 是一个 zapcore.Core 包装器，按级别对写入做令牌桶限流，
+// 超出预算的记录直接丢弃并计数，不会传递给被包装的 base core。
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+}
+
+func newRateLimitCore(base zapcore.Core, limiter *rateLimiter) *rateLimitCore {
+	return &rateLimitCore{Core: base, limiter: limiter}
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) || !c.limiter.allow(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *rateLimitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, fields)
+}
+
+// rateLimitBucket 是单个级别的令牌桶状态。
+type rateLimitBucket struct {
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	dropped int64
+}
+
+// rateLimiter 按级别维护独立的令牌桶，每秒补充 RateLimitPerSec 个令牌。
+type rateLimiter struct {
+	rate     float64
+	capacity float64
+	buckets  [7]*rateLimitBucket // index = int(zapcore.Level) + 1，覆盖 Debug(-1) 到 Fatal(5)
+}
+
+func newRateLimiter(perSec int) *rateLimiter {
+	rl := &rateLimiter{rate: float64(perSec), capacity: float64(perSec)}
+	now := time.Now()
+	for i := range rl.buckets {
+		rl.buckets[i] = &rateLimitBucket{tokens: rl.capacity, last: now}
+	}
+	return rl
+}
+
+func (rl *rateLimiter) bucketFor(level zapcore.Level) *rateLimitBucket {
+	idx := int(level) + 1
+	if idx < 0 || idx >= len(rl.buckets) {
+		idx = 0
+	}
+	return rl.buckets[idx]
+}
+
+func (rl *rateLimiter) allow(level zapcore.Level) bool {
+	b := rl.bucketFor(level)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * rl.rate
+	b.last = now
+	if b.tokens > rl.capacity {
+		b.tokens = rl.capacity
+	}
+
+	if b.tokens < 1 {
+		atomic.AddInt64(&b.dropped, 1)
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (rl *rateLimiter) droppedTotal() int64 {
+	var total int64
+	for _, b := range rl.buckets {
+		total += atomic.LoadInt64(&b.dropped)
+	}
+	return total
+}
+
+func (rl *rateLimiter) droppedByLevel() map[string]int64 {
+	result := make(map[string]int64)
+	for i, b := range rl.buckets {
+		if d := atomic.LoadInt64(&b.dropped); d > 0 {
+			result[zapcore.Level(i-1).String()] = d
+		}
+	}
+	return result
+}
+
+// applySamplingAndRateLimit 按 cfg 列表中第一个生效的 Sampling/RateLimitPerSec
+// 配置包装 core；两者都是作用于整个 logger 的全局设置，而非单个 sink。
+func applySamplingAndRateLimit(core zapcore.Core, cfg []*LoggerCfg) (zapcore.Core, *rateLimiter) {
+	for _, v := range cfg {
+		if v.Sampling != nil {
+			tick := v.Sampling.Tick
+			if tick <= 0 {
+				tick = time.Second
+			}
+			core = zapcore.NewSamplerWithOptions(core, tick, v.Sampling.Initial, v.Sampling.Thereafter)
+			break
+		}
+	}
+
+	var limiter *rateLimiter
+	for _, v := range cfg {
+		if v.RateLimitPerSec > 0 {
+			limiter = newRateLimiter(v.RateLimitPerSec)
+			core = newRateLimitCore(core, limiter)
+			break
+		}
+	}
+
+	return core, limiter
+}
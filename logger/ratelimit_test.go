@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestRateLimiterAllowsUpToCapacityThenDrops 验证令牌桶耗尽后拒绝请求并计入 dropped。
+func TestRateLimiterAllowsUpToCapacityThenDrops(t *testing.T) {
+	rl := newRateLimiter(2)
+	if !rl.allow(zapcore.InfoLevel) {
+		t.Fatal("expected first token to be allowed")
+	}
+	if !rl.allow(zapcore.InfoLevel) {
+		t.Fatal("expected second token to be allowed")
+	}
+	if rl.allow(zapcore.InfoLevel) {
+		t.Fatal("expected third call within same instant to be rate limited")
+	}
+	if got := rl.droppedTotal(); got != 1 {
+		t.Fatalf("droppedTotal = %d, want 1", got)
+	}
+}
+
+// TestRateLimiterTracksLevelsIndependently 验证不同级别各自维护独立的令牌桶。
+func TestRateLimiterTracksLevelsIndependently(t *testing.T) {
+	rl := newRateLimiter(1)
+	rl.allow(zapcore.InfoLevel)
+	rl.allow(zapcore.InfoLevel) // 超出预算，被丢弃
+
+	if !rl.allow(zapcore.ErrorLevel) {
+		t.Fatal("expected error level bucket to be independent from info level")
+	}
+
+	byLevel := rl.droppedByLevel()
+	if byLevel["info"] != 1 {
+		t.Fatalf("droppedByLevel[info] = %d, want 1", byLevel["info"])
+	}
+	if _, ok := byLevel["error"]; ok {
+		t.Fatalf("droppedByLevel should not contain error, got %v", byLevel)
+	}
+}
+
+// TestRateLimiterRefillsOverTime 验证令牌会按 rate 随时间恢复。
+func TestRateLimiterRefillsOverTime(t *testing.T) {
+	rl := newRateLimiter(5)
+	for i := 0; i < 5; i++ {
+		if !rl.allow(zapcore.DebugLevel) {
+			t.Fatalf("expected token %d to be allowed", i)
+		}
+	}
+	if rl.allow(zapcore.DebugLevel) {
+		t.Fatal("expected bucket to be empty")
+	}
+
+	time.Sleep(300 * time.Millisecond) // rate=5/s，约恢复 1.5 个令牌
+	if !rl.allow(zapcore.DebugLevel) {
+		t.Fatal("expected bucket to refill after waiting")
+	}
+}
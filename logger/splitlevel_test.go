@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestSplitLevelFilename 验证基础路径按级别拆分出的文件名格式。
+func TestSplitLevelFilename(t *testing.T) {
+	cases := []struct {
+		base  string
+		level LogLevel
+		want  string
+	}{
+		{"log/app.log", ErrorLevel, "log/app.error.log"},
+		{"log/app", WarnLevel, "log/app.warn"},
+		{"app.log", DebugLevel, "app.debug.log"},
+	}
+	for _, c := range cases {
+		if got := splitLevelFilename(c.base, c.level); got != c.want {
+			t.Errorf("splitLevelFilename(%q, %q) = %q, want %q", c.base, c.level, got, c.want)
+		}
+	}
+}
+
+// TestFirstNonZero 验证 LevelFileCfg 缺省字段回退到顶层同名配置的取值顺序。
+func TestFirstNonZero(t *testing.T) {
+	if got := firstNonZero(3, 10); got != 3 {
+		t.Errorf("firstNonZero(3, 10) = %d, want 3", got)
+	}
+	if got := firstNonZero(0, 10); got != 10 {
+		t.Errorf("firstNonZero(0, 10) = %d, want 10", got)
+	}
+}
+
+// TestBuildSplitCoresRoutesPanicAndDPanicToErrorCore 验证 Panic/DPanic 记录被路由到
+// error 级别的 core，而不是被所有 core 拒绝后静默丢弃。
+func TestBuildSplitCoresRoutesPanicAndDPanicToErrorCore(t *testing.T) {
+	cfg := &LoggerCfg{}
+	cores, _ := buildSplitCores(cfg, "app.log", zapcore.NewConsoleEncoder(zapcore.EncoderConfig{}))
+
+	var errorCore zapcore.Core
+	for i, lvl := range splitLevels {
+		if lvl == ErrorLevel {
+			errorCore = cores[i]
+		}
+	}
+	if errorCore == nil {
+		t.Fatal("expected an error-level split core")
+	}
+
+	for _, lvl := range []zapcore.Level{zapcore.ErrorLevel, zapcore.DPanicLevel, zapcore.PanicLevel} {
+		if !errorCore.Enabled(lvl) {
+			t.Errorf("expected error split core to accept level %v", lvl)
+		}
+	}
+	if errorCore.Enabled(zapcore.FatalLevel) {
+		t.Error("expected error split core to reject fatal level")
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	zap "go.uber.org/zap"
@@ -48,6 +49,8 @@ type WarpLog interface {
 	Panic(msg string, fields ...any)
 	SetAllLevel(level LogLevel)
 	SetLevel(idx int, level LogLevel)
+	SetLevelByName(name string, level LogLevel)
+	Stats() Stats
 	WithContextTrace(ctx context.Context) TraceLog
 	WithTrace(traceId string) TraceLog
 	RedirectStdLog()
@@ -91,7 +94,7 @@ func (l *LogLevel) UnmarshalYAML(node *yaml.Node) error {
 }
 func parseLogLevel(s string) (string, error) {
 	switch v := strings.ToLower(strings.TrimSpace(s)); v {
-	case "debug", "info", "warn", "warning", "error":
+	case "debug", "info", "warn", "warning", "error", "fatal":
 		if v == "warning" {
 			return "warn", nil
 		}
@@ -114,6 +117,7 @@ const (
 	InfoLevel  LogLevel = "info"
 	WarnLevel  LogLevel = "warn"
 	ErrorLevel LogLevel = "error"
+	FatalLevel LogLevel = "fatal"
 )
 
 type contextKey string
@@ -122,12 +126,54 @@ const TraceIDKey contextKey = "TraceLogId"
 
 // LoggerCfg 定义日志的配置项，支持输出路径、文件大小、压缩等。
 type LoggerCfg struct {
-	WriterFilePath []string `json:"output" yaml:"output"`         // 输出路径
+	WriterFilePath []string `json:"output" yaml:"output"`         // 输出路径，支持 "stdout"/"stderr"/文件路径/"loki"
 	Maxsize        int      `json:"maxsize" yaml:"maxsize"`       // 单个文件最大 MB
 	Maxbackups     int      `json:"maxbackups" yaml:"maxbackups"` // 最大备份文件数
 	Maxage         int      `json:"maxage" yaml:"maxage"`         // 最大保留天数
 	Compress       bool     `json:"compress" yaml:"compress"`     // 是否压缩
 	Level          LogLevel `json:"level" yaml:"level"`           // 日志等级
+
+	// 以下字段仅在 output 包含 "loki" 时生效。
+	LokiURL       string            `json:"loki_url" yaml:"loki_url"`             // Loki push 接口地址，如 http://127.0.0.1:3100/loki/api/v1/push
+	LokiLabels    map[string]string `json:"loki_labels" yaml:"loki_labels"`       // 该 stream 的标签
+	BatchSize     int               `json:"batch_size" yaml:"batch_size"`         // 达到该条数即触发一次推送，默认 100
+	FlushInterval time.Duration     `json:"flush_interval" yaml:"flush_interval"` // 达到该时长即触发一次推送，默认 3s
+	TenantID      string            `json:"tenant_id" yaml:"tenant_id"`           // 多租户场景下的 X-Scope-OrgID
+
+	// Alert 配置后，达到 MinLevel 的日志会被异步推送到对应的 IM 机器人。
+	Alert *AlertCfg `json:"alert" yaml:"alert"`
+
+	// SplitByLevel 为 true 时，忽略上面的单一 writer，改为按级别各自落盘到
+	// "{WriterFilePath[0]}.{level}.log"，每个级别可在 LevelFiles 中单独覆盖
+	// size/backup/age/compress。不开启时行为与之前完全一致。
+	SplitByLevel bool                      `json:"split_by_level" yaml:"split_by_level"`
+	LevelFiles   map[LogLevel]LevelFileCfg `json:"level_files" yaml:"level_files"`
+
+	// RotateBy 控制文件 writer 的轮转方式："size"（默认，只按体积）/"daily"/"hourly"。
+	// 为 daily/hourly 时，TimePattern（缺省取 output 中的文件路径本身）会按 Go 时间
+	// 模板格式化出当前文件名，例如 "log/app-2006-01-02.log"；同一时间段内仍由
+	// lumberjack 按 Maxsize/Maxbackups/Maxage 处理体积溢出。
+	RotateBy    string `json:"rotate_by" yaml:"rotate_by"`
+	TimePattern string `json:"time_pattern" yaml:"time_pattern"`
+
+	// Format 选择该 core 使用的编码器："console"（默认）/"json"，或通过 RegisterEncoder
+	// 注册的自定义名称。EncoderOptions 控制时间格式、级别样式、颜色及 key 名称等细节。
+	Format         string         `json:"format" yaml:"format"`
+	EncoderOptions EncoderOptions `json:"encoder_options" yaml:"encoder_options"`
+
+	// Sampling 和 RateLimitPerSec 作用于整个 logger：列表中第一个非空的 Sampling
+	// 和第一个大于 0 的 RateLimitPerSec 生效，用来在突发/热点日志路径下保护
+	// Loki、IM 告警等下游 sink。
+	Sampling        *SamplingCfg `json:"sampling" yaml:"sampling"`
+	RateLimitPerSec int          `json:"rate_limit_per_sec" yaml:"rate_limit_per_sec"`
+}
+
+// SamplingCfg 对应 zapcore.NewSamplerWithOptions 的参数：每个 Tick 时间窗口内，
+// 同一 message+level 的前 Initial 条全部记录，之后每 Thereafter 条才记录一条。
+type SamplingCfg struct {
+	Initial    int           `json:"initial" yaml:"initial"`
+	Thereafter int           `json:"thereafter" yaml:"thereafter"`
+	Tick       time.Duration `json:"tick" yaml:"tick"`
 }
 
 var (
@@ -202,7 +248,7 @@ func GetLogger(tag string) WarpLog {
 	loggerLock.RUnlock()
 	if inst == nil {
 		atomicLevel := zap.NewAtomicLevelAt(InfoLevel.LogLevel())
-		core := zapcore.NewCore(getEncoder(), zapcore.AddSync(os.Stdout), atomicLevel)
+		core := zapcore.NewCore(defaultEncoder(), zapcore.AddSync(os.Stdout), atomicLevel)
 		zapLogger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 		inst = &logger{logger: zapLogger.Sugar(), atomicLevels: []*zap.AtomicLevel{&atomicLevel}}
 		loggerLock.Lock()
@@ -225,25 +271,62 @@ func generate(tag string, cfg []*LoggerCfg, skipCall int) (WarpLog, error) {
 		return inst, nil
 	}
 
-	cores := make([]zapcore.Core, 0)
-	encoder := getEncoder()
-	var atomicLevels []*zap.AtomicLevel
+	// 提前校验每个配置项的 Format 和 SplitByLevel，避免中途失败时已经起了 goroutine 的
+	// Loki/Alert sink 悬空。
 	for _, v := range cfg {
 		if v == nil {
 			return nil, fmt.Errorf("logger config contains nil item")
 		}
-		writer := getWriter(v)
-		atomicLevel := zap.NewAtomicLevelAt(v.Level.LogLevel()) // 支持后续变更
-		core := zapcore.NewCore(encoder, writer, atomicLevel)
-		cores = append(cores, core)
-		atomicLevels = append(atomicLevels, &atomicLevel)
+		if _, err := getEncoder(v); err != nil {
+			return nil, err
+		}
+		if v.SplitByLevel && len(v.WriterFilePath) == 0 {
+			return nil, fmt.Errorf("split_by_level requires a base file path in output")
+		}
+	}
+
+	cores := make([]zapcore.Core, 0)
+	var atomicLevels []*zap.AtomicLevel
+	var closers []func()
+	var lokiWriters []*lokiWriter
+	var alertSinks []*alertSink
+	levelAtomics := make(map[string]*zap.AtomicLevel)
+	for _, v := range cfg {
+		encoder, _ := getEncoder(v) // 已在上面校验过，这里不会再出错
+
+		if v.SplitByLevel {
+			splitCores, splitAtomics := buildSplitCores(v, v.WriterFilePath[0], encoder) // 已在上面校验过 WriterFilePath 非空
+			cores = append(cores, splitCores...)
+			for name, al := range splitAtomics {
+				levelAtomics[name] = al
+			}
+		} else {
+			writer := getWriter(v, &closers, &lokiWriters)
+			atomicLevel := zap.NewAtomicLevelAt(v.Level.LogLevel()) // 支持后续变更
+			core := zapcore.NewCore(encoder, writer, atomicLevel)
+			cores = append(cores, core)
+			atomicLevels = append(atomicLevels, &atomicLevel)
+		}
+
+		if v.Alert != nil {
+			ac := newAlertCore(v.Alert)
+			cores = append(cores, ac)
+			closers = append(closers, ac.sink.Close)
+			alertSinks = append(alertSinks, ac.sink)
+		}
 	}
 
-	zapLogger := zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddCallerSkip(skipCall))
+	finalCore, limiter := applySamplingAndRateLimit(zapcore.NewTee(cores...), cfg)
+	zapLogger := zap.New(finalCore, zap.AddCaller(), zap.AddCallerSkip(skipCall))
 	sugar := zapLogger.Sugar()
 	inst = &logger{
 		logger:       sugar,
 		atomicLevels: atomicLevels,
+		closers:      closers,
+		lokiWriters:  lokiWriters,
+		alertSinks:   alertSinks,
+		levelAtomics: levelAtomics,
+		rateLimiter:  limiter,
 	}
 	loggerLock.Lock()
 	loggerMap[tag] = inst
@@ -252,25 +335,38 @@ func generate(tag string, cfg []*LoggerCfg, skipCall int) (WarpLog, error) {
 	return inst, nil
 }
 
-func getEncoder() zapcore.Encoder {
-	cfg := zap.NewProductionEncoderConfig()
-	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
-	cfg.EncodeLevel = zapcore.CapitalLevelEncoder
-	cfg.CallerKey = "caller"
-	cfg.TimeKey = "time"
-	return zapcore.NewConsoleEncoder(cfg)
+func getEncoder(cfg *LoggerCfg) (zapcore.Encoder, error) {
+	format := strings.ToLower(strings.TrimSpace(cfg.Format))
+	if format == "" {
+		format = defaultEncoderFormat
+	}
+	factory, ok := getEncoderFactory(format)
+	if !ok {
+		return nil, fmt.Errorf("unknown encoder format: %s", cfg.Format)
+	}
+	return factory(cfg.EncoderOptions), nil
 }
 
-func getWriter(cfg *LoggerCfg) zapcore.WriteSyncer {
+func getWriter(cfg *LoggerCfg, closers *[]func(), lokiWriters *[]*lokiWriter) zapcore.WriteSyncer {
 	var writers []zapcore.WriteSyncer
 
 	for _, path := range cfg.WriterFilePath {
 		path = strings.TrimSpace(path)
-		if strings.EqualFold(path, "stdout") {
+		switch {
+		case strings.EqualFold(path, "stdout"):
 			writers = append(writers, zapcore.AddSync(os.Stdout))
-		} else if strings.EqualFold(path, "stderr") {
+		case strings.EqualFold(path, "stderr"):
 			writers = append(writers, zapcore.AddSync(os.Stderr))
-		} else {
+		case strings.EqualFold(path, "loki"):
+			lw := newLokiWriter(cfg)
+			*closers = append(*closers, lw.Close)
+			*lokiWriters = append(*lokiWriters, lw)
+			writers = append(writers, lw)
+		case cfg.RotateBy == RotateByDaily || cfg.RotateBy == RotateByHourly:
+			drw := newDateRotateWriter(cfg, path)
+			*closers = append(*closers, drw.Close)
+			writers = append(writers, drw)
+		default:
 			writers = append(writers, zapcore.AddSync(&lumberjack.Logger{
 				Filename:   path,
 				MaxSize:    cfg.Maxsize,
@@ -287,13 +383,22 @@ func getWriter(cfg *LoggerCfg) zapcore.WriteSyncer {
 type logger struct {
 	logger       *zap.SugaredLogger
 	atomicLevels []*zap.AtomicLevel
+	closers      []func() // 关闭日志时需要额外清理/flush 的资源（如 Loki writer、告警 sink）
+	lokiWriters  []*lokiWriter
+	alertSinks   []*alertSink
+	levelAtomics map[string]*zap.AtomicLevel // SplitByLevel 下按级别名索引的 AtomicLevel
+	rateLimiter  *rateLimiter                // 配置了 RateLimitPerSec 时非 nil
 	WarpLog
 }
 
 // key:TraceIDKey
+// 若 ctx 携带有效的 OTel span（见 extractOtelSpan），则后续日志会注入其
+// traceId/spanId/traceFlags；否则回退到原有的 TraceIDKey UUID 行为。
 func (l *logger) WithContextTrace(ctx context.Context) TraceLog {
-	if traceId := ctx.Value(TraceIDKey); traceId == nil {
-		ctx = context.WithValue(ctx, TraceIDKey, uuid.NewString())
+	if _, _, _, ok := extractOtelSpan(ctx); !ok {
+		if traceId := ctx.Value(TraceIDKey); traceId == nil {
+			ctx = context.WithValue(ctx, TraceIDKey, uuid.NewString())
+		}
 	}
 	return &traceLogger{
 		base: l,
@@ -350,10 +455,36 @@ func (l *logger) SetLevel(idx int, level LogLevel) {
 		l.atomicLevels[idx].SetLevel(level.LogLevel())
 	}
 }
+
+// SetLevelByName 调整某个 SplitByLevel 核心所匹配的日志级别，name 为 "debug"/"info"/"warn"/"error"/"fatal"。
+func (l *logger) SetLevelByName(name string, level LogLevel) {
+	if al, ok := l.levelAtomics[strings.ToLower(strings.TrimSpace(name))]; ok {
+		al.SetLevel(level.LogLevel())
+	}
+}
+
+// Stats 汇总 Loki/告警 sink 的丢弃数和 RateLimitPerSec 的限流丢弃数，用于监控热点日志路径。
+func (l *logger) Stats() Stats {
+	var s Stats
+	for _, lw := range l.lokiWriters {
+		s.LokiDropped += lw.DroppedCount()
+	}
+	for _, as := range l.alertSinks {
+		s.AlertDropped += as.DroppedCount()
+	}
+	if l.rateLimiter != nil {
+		s.RateLimited = l.rateLimiter.droppedTotal()
+		s.RateLimitedByLevel = l.rateLimiter.droppedByLevel()
+	}
+	return s
+}
 func (l *logger) Close() {
 	if l.logger != nil {
 		_ = l.logger.Sync()
 	}
+	for _, c := range l.closers {
+		c()
+	}
 }
 
 type traceLogger struct {
@@ -392,6 +523,9 @@ func (c *traceLogger) Panic(msg string, fields ...any) {
 }
 
 func (c *traceLogger) injectTraceId(fields []any) []any {
+	if traceID, spanID, traceFlags, ok := extractOtelSpan(c.ctx); ok {
+		return append(fields, "traceId", traceID, "spanId", spanID, "traceFlags", traceFlags)
+	}
 	if traceId, ok := c.ctx.Value(TraceIDKey).(string); ok && traceId != "" {
 		fields = append(fields, "traceId", traceId)
 	}